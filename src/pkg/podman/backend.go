@@ -0,0 +1,101 @@
+/*
+ * Copyright © 2019 – 2020 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"context"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Backend is implemented by the different ways toolbox can talk to Podman. cliBackend
+// shells out to the podman binary, the way toolbox has always worked. restBackend speaks
+// the libpod REST API directly over the user's Podman socket, which avoids the fork/exec
+// overhead of a CLI call and works just as well against a remote Podman machine.
+type Backend interface {
+	// GetContainers returns the dynamically unmarshalled output of `podman ps --format json`,
+	// with args passed through as additional filters.
+	GetContainers(args ...string) ([]map[string]interface{}, error)
+
+	// GetContainersContext behaves like GetContainers, but aborts the underlying call when
+	// ctx is cancelled instead of blocking until it finishes on its own.
+	GetContainersContext(ctx context.Context, args ...string) ([]map[string]interface{}, error)
+
+	// GetImages returns the dynamically unmarshalled output of `podman images --format json`,
+	// with args passed through as additional filters.
+	GetImages(args ...string) ([]map[string]interface{}, error)
+
+	// GetImagesContext behaves like GetImages, but aborts the underlying call when ctx is
+	// cancelled instead of blocking until it finishes on its own.
+	GetImagesContext(ctx context.Context, args ...string) ([]map[string]interface{}, error)
+
+	// GetVersion returns the version of Podman the backend is talking to.
+	GetVersion() (string, error)
+
+	// Inspect returns the dynamically unmarshalled output of `podman inspect` for a single
+	// container or image, selected through typearg ('container' or 'image').
+	Inspect(typearg string, target string) (map[string]interface{}, error)
+
+	// InspectContext behaves like Inspect, but aborts the underlying call when ctx is
+	// cancelled instead of blocking until it finishes on its own.
+	InspectContext(ctx context.Context, typearg string, target string) (map[string]interface{}, error)
+
+	// InspectContainers inspects every container in targets, returning a map keyed by the
+	// target strings as passed in. Implementations are expected to do this more cheaply
+	// than one InspectContext call per target where the underlying transport allows it.
+	InspectContainers(ctx context.Context, targets []string) (map[string]ContainerInspect, error)
+
+	// SystemMigrate re-executes the OCI runtime migration performed by `podman system migrate`.
+	SystemMigrate(ociRuntimeRequired string) error
+}
+
+// backend is the Backend used by the package-level wrapper functions in podman.go. It
+// defaults to the CLI backend and is only switched to the REST backend by SetBackend or
+// by UseRESTBackendFromEnvironment below, so that existing callers keep working unchanged.
+var backend Backend = newCLIBackend()
+
+// SetBackend overrides the Backend used by the package-level wrapper functions. It exists
+// mainly so callers (and tests) can opt into the REST backend, or point toolbox at a
+// specific Podman socket, without waiting for Podman version detection to decide for them.
+func SetBackend(b Backend) {
+	backend = b
+}
+
+// UseRESTBackendFromEnvironment switches the package-level backend to the REST backend
+// when TOOLBOX_PODMAN_SOCKET is set in the environment, auto-starting `podman system
+// service` if the socket doesn't exist yet. It is a no-op otherwise, leaving the CLI
+// backend in place.
+//
+// This must be called explicitly by the application (eg. once from main), never from an
+// init function: merely importing this package must not have the side effect of spawning
+// a background podman process just because some unrelated caller happened to have
+// TOOLBOX_PODMAN_SOCKET set.
+func UseRESTBackendFromEnvironment() {
+	socketPath := os.Getenv("TOOLBOX_PODMAN_SOCKET")
+	if socketPath == "" {
+		return
+	}
+
+	restBackend, err := newRESTBackend(socketPath)
+	if err != nil {
+		logrus.Warnf("failed to initialize REST backend for %s: %s", socketPath, err)
+		return
+	}
+
+	backend = restBackend
+}
@@ -0,0 +1,178 @@
+/*
+ * Copyright © 2019 – 2020 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/containers/toolbox/pkg/shell"
+)
+
+// cliBackend is the original Backend implementation: it shells out to the podman binary
+// for every call.
+type cliBackend struct{}
+
+func newCLIBackend() *cliBackend {
+	return &cliBackend{}
+}
+
+func (b *cliBackend) GetContainers(args ...string) ([]map[string]interface{}, error) {
+	return b.GetContainersContext(context.Background(), args...)
+}
+
+func (b *cliBackend) GetContainersContext(ctx context.Context, args ...string) ([]map[string]interface{}, error) {
+	var stdout bytes.Buffer
+
+	logLevelString := LogLevel.String()
+	args = append([]string{"--log-level", logLevelString, "ps", "--format", "json"}, args...)
+
+	if err := shell.RunContext(ctx, "podman", nil, &stdout, nil, args...); err != nil {
+		return nil, err
+	}
+
+	output := stdout.Bytes()
+	var containers []map[string]interface{}
+
+	if err := json.Unmarshal(output, &containers); err != nil {
+		return nil, err
+	}
+
+	return containers, nil
+}
+
+func (b *cliBackend) GetImages(args ...string) ([]map[string]interface{}, error) {
+	return b.GetImagesContext(context.Background(), args...)
+}
+
+func (b *cliBackend) GetImagesContext(ctx context.Context, args ...string) ([]map[string]interface{}, error) {
+	var stdout bytes.Buffer
+
+	logLevelString := LogLevel.String()
+	args = append([]string{"--log-level", logLevelString, "images", "--format", "json"}, args...)
+
+	if err := shell.RunContext(ctx, "podman", nil, &stdout, nil, args...); err != nil {
+		return nil, err
+	}
+
+	output := stdout.Bytes()
+	var images []map[string]interface{}
+
+	if err := json.Unmarshal(output, &images); err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+func (b *cliBackend) GetVersion() (string, error) {
+	var stdout bytes.Buffer
+
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "version", "--format", "json"}
+
+	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+		return "", err
+	}
+
+	output := stdout.Bytes()
+	var jsonoutput map[string]interface{}
+	if err := json.Unmarshal(output, &jsonoutput); err != nil {
+		return "", err
+	}
+
+	var podmanVersion string
+	podmanClientInfoInterface := jsonoutput["Client"]
+	switch podmanClientInfo := podmanClientInfoInterface.(type) {
+	case nil:
+		podmanVersion = jsonoutput["Version"].(string)
+	case map[string]interface{}:
+		podmanVersion = podmanClientInfo["Version"].(string)
+	}
+	return podmanVersion, nil
+}
+
+func (b *cliBackend) Inspect(typearg string, target string) (map[string]interface{}, error) {
+	return b.InspectContext(context.Background(), typearg, target)
+}
+
+func (b *cliBackend) InspectContext(ctx context.Context, typearg string, target string) (map[string]interface{}, error) {
+	var stdout bytes.Buffer
+
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "inspect", "--format", "json", "--type", typearg, target}
+
+	if err := shell.RunContext(ctx, "podman", nil, &stdout, nil, args...); err != nil {
+		return nil, err
+	}
+
+	output := stdout.Bytes()
+	var info []map[string]interface{}
+
+	if err := json.Unmarshal(output, &info); err != nil {
+		return nil, err
+	}
+
+	return info[0], nil
+}
+
+func (b *cliBackend) InspectContainers(ctx context.Context, targets []string) (map[string]ContainerInspect, error) {
+	results := make(map[string]ContainerInspect, len(targets))
+	if len(targets) == 0 {
+		return results, nil
+	}
+
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "inspect", "--format", "json", "--type", "container"}
+	args = append(args, targets...)
+
+	var stdout bytes.Buffer
+	if err := shell.RunContext(ctx, "podman", nil, &stdout, nil, args...); err != nil {
+		return nil, err
+	}
+
+	var inspected []ContainerInspect
+	if err := json.Unmarshal(stdout.Bytes(), &inspected); err != nil {
+		return nil, err
+	}
+
+	if len(inspected) != len(targets) {
+		return nil, fmt.Errorf("podman inspect returned %d results for %d targets", len(inspected), len(targets))
+	}
+
+	for i, target := range targets {
+		results[target] = inspected[i]
+	}
+
+	return results, nil
+}
+
+func (b *cliBackend) SystemMigrate(ociRuntimeRequired string) error {
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "system", "migrate"}
+	if ociRuntimeRequired != "" {
+		args = append(args, []string{"--new-runtime", ociRuntimeRequired}...)
+	}
+
+	if err := shell.Run("podman", nil, nil, nil, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
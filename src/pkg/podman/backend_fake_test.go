@@ -0,0 +1,81 @@
+/*
+ * Copyright © 2019 – 2020 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"context"
+	"errors"
+)
+
+// errFakeVersionUnreachable is a value for fakeBackend.versionErr, which GetVersion then
+// returns, standing in for Podman/its socket being unreachable.
+var errFakeVersionUnreachable = errors.New("fake: podman unreachable")
+
+// fakeBackend is a minimal Backend double used by tests that need to control what
+// GetVersion (or another call) returns without shelling out to a real podman.
+type fakeBackend struct {
+	version    string
+	versionErr error
+
+	// inspectContainersTargets and inspectContainersResult/inspectContainersErr record
+	// and control InspectContainers, for tests asserting that a caller dispatches to the
+	// Backend instead of bypassing it.
+	inspectContainersTargets []string
+	inspectContainersResult  map[string]ContainerInspect
+	inspectContainersErr     error
+}
+
+func (f *fakeBackend) GetContainers(args ...string) ([]map[string]interface{}, error) {
+	return f.GetContainersContext(context.Background(), args...)
+}
+
+func (f *fakeBackend) GetContainersContext(ctx context.Context, args ...string) ([]map[string]interface{}, error) {
+	return nil, nil
+}
+
+func (f *fakeBackend) GetImages(args ...string) ([]map[string]interface{}, error) {
+	return f.GetImagesContext(context.Background(), args...)
+}
+
+func (f *fakeBackend) GetImagesContext(ctx context.Context, args ...string) ([]map[string]interface{}, error) {
+	return nil, nil
+}
+
+func (f *fakeBackend) GetVersion() (string, error) {
+	if f.versionErr != nil {
+		return "", f.versionErr
+	}
+
+	return f.version, nil
+}
+
+func (f *fakeBackend) Inspect(typearg string, target string) (map[string]interface{}, error) {
+	return f.InspectContext(context.Background(), typearg, target)
+}
+
+func (f *fakeBackend) InspectContext(ctx context.Context, typearg string, target string) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+func (f *fakeBackend) InspectContainers(ctx context.Context, targets []string) (map[string]ContainerInspect, error) {
+	f.inspectContainersTargets = targets
+	return f.inspectContainersResult, f.inspectContainersErr
+}
+
+func (f *fakeBackend) SystemMigrate(ociRuntimeRequired string) error {
+	return nil
+}
@@ -0,0 +1,295 @@
+/*
+ * Copyright © 2019 – 2020 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// libpodAPIVersion is the version segment of the libpod REST API endpoints toolbox talks
+// to (e.g. /v4.0.0/libpod/containers/json). The same version is used for every request,
+// whatever the version of the Podman serving the socket; the libpod API is additive across
+// versions, so this is safe to pin.
+const libpodAPIVersion = "v4.0.0"
+
+// socketStartupTimeout bounds how long restBackend waits for `podman system service` to
+// create its socket after being spawned.
+const socketStartupTimeout = 5 * time.Second
+
+// restBackend talks to Podman over the libpod REST API exposed on a UNIX socket (eg.
+// $XDG_RUNTIME_DIR/podman/podman.sock), instead of shelling out to the podman binary for
+// every call. Besides avoiding fork/exec overhead, this is the same transport used by the
+// upstream `podman --remote` client, so it works unmodified against a remote Podman
+// machine reachable over the same socket path (eg. via podman-machine's SSH tunnel).
+type restBackend struct {
+	socketPath string
+	client     *http.Client
+}
+
+// newRESTBackend returns a restBackend talking to the Podman socket at socketPath,
+// starting `podman system service` on demand if the socket doesn't exist yet.
+func newRESTBackend(socketPath string) (*restBackend, error) {
+	if _, err := os.Stat(socketPath); err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		if err := startPodmanSystemService(socketPath); err != nil {
+			return nil, fmt.Errorf("failed to start podman system service: %w", err)
+		}
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+
+	return &restBackend{socketPath: socketPath, client: client}, nil
+}
+
+// startPodmanSystemService spawns `podman system service` in the background and waits for
+// it to create socketPath, so that callers immediately after newRESTBackend can rely on
+// the socket being ready. The spawned process is left running (it serves the socket for as
+// long as toolbox needs it), but is reaped in the background as soon as it exits so it
+// never lingers as a zombie.
+func startPodmanSystemService(socketPath string) error {
+	cmd := exec.Command("podman", "system", "service", "--time=0", fmt.Sprintf("unix://%s", socketPath))
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	pid := cmd.Process.Pid
+	logrus.Debugf("started podman system service (pid %d) for socket %s", pid, socketPath)
+
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			logrus.Debugf("podman system service (pid %d) exited: %s", pid, err)
+		}
+	}()
+
+	deadline := time.Now().Add(socketStartupTimeout)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(socketPath); err == nil {
+			return nil
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	return fmt.Errorf("timed out waiting for %s to appear", socketPath)
+}
+
+// filtersFromArgs translates the subset of `podman ps`/`podman images` flags toolbox
+// actually uses into the REST API's query parameters: "-a"/"--all" becomes "all=true", and
+// "-f"/"--filter KEY=VALUE" is collected into a "filters" parameter holding a JSON-encoded
+// map[string][]string, exactly as the libpod API expects it. Any other flag is rejected
+// rather than silently ignored, so the CLI and REST backends can't drift into returning
+// different results for the same args.
+func filtersFromArgs(args []string) (url.Values, error) {
+	query := url.Values{}
+	filters := map[string][]string{}
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-a", "--all":
+			query.Set("all", "true")
+		case "-f", "--filter":
+			i++
+			if i >= len(args) {
+				return nil, fmt.Errorf("podman: %s requires a value", args[i-1])
+			}
+
+			key, value, ok := strings.Cut(args[i], "=")
+			if !ok {
+				return nil, fmt.Errorf("podman: invalid filter %q, expected KEY=VALUE", args[i])
+			}
+
+			filters[key] = append(filters[key], value)
+		default:
+			return nil, fmt.Errorf("podman: REST backend does not support argument %q", args[i])
+		}
+	}
+
+	if len(filters) > 0 {
+		encoded, err := json.Marshal(filters)
+		if err != nil {
+			return nil, err
+		}
+
+		query.Set("filters", string(encoded))
+	}
+
+	return query, nil
+}
+
+func (b *restBackend) get(ctx context.Context, path string, query url.Values, result interface{}) error {
+	endpoint := fmt.Sprintf("http://d/%s/libpod%s", libpodAPIVersion, path)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("podman socket returned %s: %s", resp.Status, body)
+	}
+
+	return json.Unmarshal(body, result)
+}
+
+func (b *restBackend) GetContainers(args ...string) ([]map[string]interface{}, error) {
+	return b.GetContainersContext(context.Background(), args...)
+}
+
+func (b *restBackend) GetContainersContext(ctx context.Context, args ...string) ([]map[string]interface{}, error) {
+	query, err := filtersFromArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var containers []map[string]interface{}
+	if err := b.get(ctx, "/containers/json", query, &containers); err != nil {
+		return nil, err
+	}
+
+	return containers, nil
+}
+
+func (b *restBackend) GetImages(args ...string) ([]map[string]interface{}, error) {
+	return b.GetImagesContext(context.Background(), args...)
+}
+
+func (b *restBackend) GetImagesContext(ctx context.Context, args ...string) ([]map[string]interface{}, error) {
+	query, err := filtersFromArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var images []map[string]interface{}
+	if err := b.get(ctx, "/images/json", query, &images); err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+func (b *restBackend) GetVersion() (string, error) {
+	var info map[string]interface{}
+	if err := b.get(context.Background(), "/version", nil, &info); err != nil {
+		return "", err
+	}
+
+	version, _ := info["Version"].(string)
+	return version, nil
+}
+
+func (b *restBackend) Inspect(typearg string, target string) (map[string]interface{}, error) {
+	return b.InspectContext(context.Background(), typearg, target)
+}
+
+func (b *restBackend) InspectContext(ctx context.Context, typearg string, target string) (map[string]interface{}, error) {
+	var info map[string]interface{}
+	path := fmt.Sprintf("/%ss/%s/json", url.PathEscape(typearg), url.PathEscape(target))
+	if err := b.get(ctx, path, nil, &info); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// InspectContainers fans a goroutine per target out over InspectContext, since the libpod
+// REST API has no batch-inspect endpoint. It still saves toolbox the fork/exec overhead
+// the CLI backend's single InspectContainers call is really after, just by parallelizing
+// N HTTP round trips over the same socket rather than avoiding them.
+func (b *restBackend) InspectContainers(ctx context.Context, targets []string) (map[string]ContainerInspect, error) {
+	results := make(map[string]ContainerInspect, len(targets))
+	if len(targets) == 0 {
+		return results, nil
+	}
+
+	type inspected struct {
+		target string
+		info   ContainerInspect
+		err    error
+	}
+
+	out := make(chan inspected, len(targets))
+	for _, target := range targets {
+		target := target
+
+		go func() {
+			raw, err := b.InspectContext(ctx, "container", target)
+			if err != nil {
+				out <- inspected{target: target, err: err}
+				return
+			}
+
+			var info ContainerInspect
+			if err := reencode(raw, &info); err != nil {
+				out <- inspected{target: target, err: err}
+				return
+			}
+
+			out <- inspected{target: target, info: info}
+		}()
+	}
+
+	for range targets {
+		result := <-out
+		if result.err != nil {
+			return nil, result.err
+		}
+
+		results[result.target] = result.info
+	}
+
+	return results, nil
+}
+
+func (b *restBackend) SystemMigrate(ociRuntimeRequired string) error {
+	return fmt.Errorf("system migrate is not supported over the libpod REST API; use the CLI backend")
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright © 2019 – 2020 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFiltersFromArgsAll(t *testing.T) {
+	query, err := filtersFromArgs([]string{"-a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if query.Get("all") != "true" {
+		t.Errorf("got all=%q, want all=true", query.Get("all"))
+	}
+}
+
+func TestFiltersFromArgsFilter(t *testing.T) {
+	query, err := filtersFromArgs([]string{"--filter", "label=com.github.containers.toolbox=true"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	got := query.Get("filters")
+	want := `{"label":["com.github.containers.toolbox=true"]}`
+	if got != want {
+		t.Errorf("got filters=%s, want %s", got, want)
+	}
+}
+
+func TestFiltersFromArgsMultipleFiltersSameKey(t *testing.T) {
+	query, err := filtersFromArgs([]string{"-f", "status=running", "-f", "status=paused"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	got := query.Get("filters")
+	want := `{"status":["running","paused"]}`
+	if got != want {
+		t.Errorf("got filters=%s, want %s", got, want)
+	}
+}
+
+func TestFiltersFromArgsRejectsUnsupportedFlag(t *testing.T) {
+	if _, err := filtersFromArgs([]string{"--latest"}); err == nil {
+		t.Error("expected an error for an unsupported argument, got nil")
+	}
+}
+
+func TestFiltersFromArgsRejectsMalformedFilter(t *testing.T) {
+	if _, err := filtersFromArgs([]string{"--filter", "not-a-key-value-pair"}); err == nil {
+		t.Error("expected an error for a malformed filter, got nil")
+	}
+}
+
+func TestFiltersFromArgsRejectsDanglingFilterFlag(t *testing.T) {
+	if _, err := filtersFromArgs([]string{"--filter"}); err == nil {
+		t.Error("expected an error for a --filter with no value, got nil")
+	}
+}
+
+// TestInspectContextEscapesPath ensures a target (or typearg) containing characters that
+// would otherwise be interpreted as path separators, like "/", gets percent-encoded into a
+// single path segment rather than changing which endpoint is requested.
+func TestInspectContextEscapesPath(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	b := &restBackend{
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "tcp", server.Listener.Addr().String())
+				},
+			},
+		},
+	}
+
+	if _, err := b.InspectContext(context.Background(), "container", "weird/../target"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	const want = "/v4.0.0/libpod/containers/weird%2F..%2Ftarget/json"
+	if gotPath != want {
+		t.Errorf("got request path %q, want %q", gotPath, want)
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright © 2019 – 2020 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetContainersContext behaves like GetContainers, but aborts as soon as ctx is cancelled
+// instead of blocking indefinitely on a hung podman call.
+func GetContainersContext(ctx context.Context, args ...string) ([]map[string]interface{}, error) {
+	return backend.GetContainersContext(ctx, args...)
+}
+
+// GetImagesContext behaves like GetImages, but aborts as soon as ctx is cancelled instead
+// of blocking indefinitely on a hung podman call.
+func GetImagesContext(ctx context.Context, args ...string) ([]map[string]interface{}, error) {
+	return backend.GetImagesContext(ctx, args...)
+}
+
+// InspectContext behaves like Inspect, but aborts as soon as ctx is cancelled instead of
+// blocking indefinitely on a hung podman call.
+func InspectContext(ctx context.Context, typearg string, target string) (map[string]interface{}, error) {
+	return backend.InspectContext(ctx, typearg, target)
+}
+
+// InspectMany inspects every target in targets with one InspectContainers call on the
+// active Backend: the CLI backend issues a single `podman inspect` for all of them, while
+// the REST backend issues one inspect request per target, in parallel. It's meant for
+// toolbox's startup path, where every managed container needs to be inspected at once.
+//
+// Only typearg "container" is supported, since the result is always decoded into
+// ContainerInspect; passing any other typearg is a programmer error and returns one
+// instead of silently producing empty/garbage ContainerInspect values.
+//
+// The returned map is keyed by the target strings as passed in.
+func InspectMany(ctx context.Context, typearg string, targets []string) (map[string]ContainerInspect, error) {
+	if typearg != "container" {
+		return nil, fmt.Errorf("podman: InspectMany only supports typearg \"container\", got %q", typearg)
+	}
+
+	return backend.InspectContainers(ctx, targets)
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright © 2019 – 2020 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestInspectManyDispatchesToBackend(t *testing.T) {
+	origBackend := backend
+	defer func() { backend = origBackend }()
+
+	want := map[string]ContainerInspect{
+		"toolbox-fedora": {ID: "abc123"},
+	}
+	fake := &fakeBackend{inspectContainersResult: want}
+	backend = fake
+
+	targets := []string{"toolbox-fedora"}
+	got, err := InspectMany(context.Background(), "container", targets)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(fake.inspectContainersTargets, targets) {
+		t.Errorf("InspectContainers called with %v, want %v", fake.inspectContainersTargets, targets)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestInspectManyRejectsNonContainerTypearg(t *testing.T) {
+	origBackend := backend
+	defer func() { backend = origBackend }()
+
+	fake := &fakeBackend{}
+	backend = fake
+
+	if _, err := InspectMany(context.Background(), "image", []string{"fedora-toolbox:38"}); err == nil {
+		t.Fatal("expected an error for typearg \"image\", got nil")
+	}
+
+	if fake.inspectContainersTargets != nil {
+		t.Errorf("InspectContainers was called with %v, want it not to be called", fake.inspectContainersTargets)
+	}
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright © 2019 – 2020 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// eventActor is the shape Podman puts under an event's "Actor" key.
+type eventActor struct {
+	ID         string            `json:"ID"`
+	Attributes map[string]string `json:"Attributes"`
+}
+
+// Event is a single line of `podman events --format json` output, eg. a container being
+// started or removed, or an image being pulled.
+type Event struct {
+	Type       string
+	Action     string
+	Actor      string
+	Time       int64
+	Attributes map[string]string
+}
+
+// UnmarshalJSON pulls Actor and Attributes out of Podman's nested "Actor" object, so
+// callers get the actor's ID and its attributes as plain Event fields.
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type   string     `json:"Type"`
+		Action string     `json:"Action"`
+		Actor  eventActor `json:"Actor"`
+		Time   int64      `json:"time"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	e.Type = raw.Type
+	e.Action = raw.Action
+	e.Actor = raw.Actor.ID
+	e.Attributes = raw.Actor.Attributes
+	e.Time = raw.Time
+	return nil
+}
+
+// SubscribeEvents streams Podman's event log by running `podman events --format json
+// --stream=true` and decoding one JSON object per line. Passing filters (eg.
+// []string{"type=container"}) narrows the stream down the same way `--filter` would.
+//
+// The returned events channel is closed when ctx is cancelled or the underlying podman
+// process exits, whichever happens first. The returned errs channel receives at most one
+// error — why the process exited, with its stderr output attached if there was any — and
+// is closed right after, so a caller can tell a clean end of stream (errs closed with
+// nothing sent) apart from podman dying underneath it (eg. a bad filter, or the socket
+// going away). Once the REST backend from the CLI/REST split gains support for the
+// /libpod/events endpoint, this can grow a restBackend-backed implementation that skips
+// the subprocess entirely.
+func SubscribeEvents(ctx context.Context, filters []string) (<-chan Event, <-chan error, error) {
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "events", "--format", "json", "--stream=true"}
+	for _, filter := range filters {
+		args = append(args, "--filter", filter)
+	}
+
+	cmd := exec.CommandContext(ctx, "podman", args...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, nil, err
+	}
+
+	events := make(chan Event)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+
+		scanner := bufio.NewScanner(stdout)
+		var scanErr error
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			if len(line) == 0 {
+				continue
+			}
+
+			var event Event
+			if err := json.Unmarshal(line, &event); err != nil {
+				logrus.Warnf("failed to decode podman event %q: %s", line, err)
+				continue
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				_, _ = io.Copy(io.Discard, stdout)
+				cmd.Wait()
+				return
+			}
+		}
+
+		scanErr = scanner.Err()
+
+		// cmd.Wait closes stdout only after the child has exited and its output has
+		// been fully read; since the scan loop above may have stopped early (eg. on
+		// scanErr), drain whatever's left so the child isn't stuck blocked on a full
+		// pipe buffer.
+		_, _ = io.Copy(io.Discard, stdout)
+
+		waitErr := cmd.Wait()
+		if scanErr != nil {
+			errs <- fmt.Errorf("failed to read podman events output: %w", scanErr)
+			return
+		}
+
+		if waitErr == nil {
+			return
+		}
+
+		if stderrText := strings.TrimSpace(stderr.String()); stderrText != "" {
+			waitErr = fmt.Errorf("%w: %s", waitErr, stderrText)
+		}
+
+		errs <- waitErr
+	}()
+
+	return events, errs, nil
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright © 2019 – 2020 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventUnmarshalJSON(t *testing.T) {
+	raw := `{
+		"Type": "container",
+		"Action": "start",
+		"Actor": {
+			"ID": "abc123",
+			"Attributes": {"name": "toolbox-fedora"}
+		},
+		"time": 1234567890
+	}`
+
+	var event Event
+	if err := json.Unmarshal([]byte(raw), &event); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := Event{
+		Type:       "container",
+		Action:     "start",
+		Actor:      "abc123",
+		Time:       1234567890,
+		Attributes: map[string]string{"name": "toolbox-fedora"},
+	}
+
+	if !reflect.DeepEqual(event, want) {
+		t.Errorf("got %+v, want %+v", event, want)
+	}
+}
+
+func TestEventUnmarshalJSONNoAttributes(t *testing.T) {
+	raw := `{"Type":"image","Action":"pull","Actor":{"ID":"img1"},"time":1}`
+
+	var event Event
+	if err := json.Unmarshal([]byte(raw), &event); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if event.Actor != "img1" {
+		t.Errorf("got Actor=%q, want %q", event.Actor, "img1")
+	}
+	if event.Attributes != nil {
+		t.Errorf("got Attributes=%v, want nil", event.Attributes)
+	}
+}
+
+// TestSubscribeEventsScanErrorDoesNotHang reproduces a `podman events` line longer than
+// bufio.Scanner's default 64KB token limit: the scan loop stops with bufio.ErrTooLong while
+// the child is still writing, and the stdout pipe must be drained before cmd.Wait() can
+// return. It asserts SubscribeEvents surfaces the scan error on errs instead of hanging.
+func TestSubscribeEventsScanErrorDoesNotHang(t *testing.T) {
+	fakePodman := filepath.Join(t.TempDir(), "podman")
+	script := "#!/bin/sh\n" +
+		"head -c 70000 /dev/zero | tr '\\0' 'a'\n" +
+		"echo\n"
+	if err := os.WriteFile(fakePodman, []byte(script), 0o755); err != nil {
+		t.Fatalf("failed to write fake podman: %s", err)
+	}
+
+	origPath := os.Getenv("PATH")
+	t.Setenv("PATH", filepath.Dir(fakePodman)+string(os.PathListSeparator)+origPath)
+
+	events, errs, err := SubscribeEvents(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Errorf("got an event, want events closed without any")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("events was not closed in time")
+	}
+
+	select {
+	case err, ok := <-errs:
+		if !ok {
+			t.Fatal("errs was closed without an error, want the scan error")
+		}
+		if !strings.Contains(err.Error(), "failed to read podman events output") {
+			t.Errorf("got error %q, want it to mention the scan failure", err)
+		}
+		if !errors.Is(err, bufio.ErrTooLong) {
+			t.Errorf("got error %q, want it to wrap bufio.ErrTooLong", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("errs did not receive the scan error in time")
+	}
+}
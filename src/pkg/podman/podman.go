@@ -17,11 +17,6 @@
 package podman
 
 import (
-	"bytes"
-	"encoding/json"
-
-	"github.com/HarryMichal/go-version"
-	"github.com/containers/toolbox/pkg/shell"
 	"github.com/sirupsen/logrus"
 )
 
@@ -35,12 +30,19 @@ var (
 //
 // Returns true if the Podman version is equal to or higher than the required version.
 func CheckVersion(requiredVersion string) bool {
-	podmanVersion, _ := GetVersion()
+	podmanVersionString, _ := GetVersion()
 
-	podmanVersion = version.Normalize(podmanVersion)
-	requiredVersion = version.Normalize(requiredVersion)
+	podmanVersion, err := ParseVersion(podmanVersionString)
+	if err != nil {
+		return false
+	}
 
-	return version.CompareSimple(podmanVersion, requiredVersion) >= 0
+	required, err := ParseVersion(requiredVersion)
+	if err != nil {
+		return false
+	}
+
+	return podmanVersion.GTE(required)
 }
 
 // GetContainers is a wrapper function around `podman ps --format json` command.
@@ -51,23 +53,7 @@ func CheckVersion(requiredVersion string) bool {
 //
 // If a problem happens during execution, first argument is nil and second argument holds the error message.
 func GetContainers(args ...string) ([]map[string]interface{}, error) {
-	var stdout bytes.Buffer
-
-	logLevelString := LogLevel.String()
-	args = append([]string{"--log-level", logLevelString, "ps", "--format", "json"}, args...)
-
-	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
-		return nil, err
-	}
-
-	output := stdout.Bytes()
-	var containers []map[string]interface{}
-
-	if err := json.Unmarshal(output, &containers); err != nil {
-		return nil, err
-	}
-
-	return containers, nil
+	return backend.GetContainers(args...)
 }
 
 // GetImages is a wrapper function around `podman images --format json` command.
@@ -78,73 +64,19 @@ func GetContainers(args ...string) ([]map[string]interface{}, error) {
 //
 // If a problem happens during execution, first argument is nil and second argument holds the error message.
 func GetImages(args ...string) ([]map[string]interface{}, error) {
-	var stdout bytes.Buffer
-
-	logLevelString := LogLevel.String()
-	args = append([]string{"--log-level", logLevelString, "images", "--format", "json"}, args...)
-	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
-		return nil, err
-	}
-
-	output := stdout.Bytes()
-	var images []map[string]interface{}
-
-	if err := json.Unmarshal(output, &images); err != nil {
-		return nil, err
-	}
-
-	return images, nil
+	return backend.GetImages(args...)
 }
 
 // GetVersion returns version of Podman in a string
 func GetVersion() (string, error) {
-	var stdout bytes.Buffer
-
-	logLevelString := LogLevel.String()
-	args := []string{"--log-level", logLevelString, "version", "--format", "json"}
-
-	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
-		return "", err
-	}
-
-	output := stdout.Bytes()
-	var jsonoutput map[string]interface{}
-	if err := json.Unmarshal(output, &jsonoutput); err != nil {
-		return "", err
-	}
-
-	var podmanVersion string
-	podmanClientInfoInterface := jsonoutput["Client"]
-	switch podmanClientInfo := podmanClientInfoInterface.(type) {
-	case nil:
-		podmanVersion = jsonoutput["Version"].(string)
-	case map[string]interface{}:
-		podmanVersion = podmanClientInfo["Version"].(string)
-	}
-	return podmanVersion, nil
+	return backend.GetVersion()
 }
 
 // Inspect is a wrapper around 'podman inspect' command
 //
 // Parameter 'typearg' takes in values 'container' or 'image' that is passed to the --type flag
 func Inspect(typearg string, target string) (map[string]interface{}, error) {
-	var stdout bytes.Buffer
-
-	logLevelString := LogLevel.String()
-	args := []string{"--log-level", logLevelString, "inspect", "--format", "json", "--type", typearg, target}
-
-	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
-		return nil, err
-	}
-
-	output := stdout.Bytes()
-	var info []map[string]interface{}
-
-	if err := json.Unmarshal(output, &info); err != nil {
-		return nil, err
-	}
-
-	return info[0], nil
+	return backend.Inspect(typearg, target)
 }
 
 func SetLogLevel(logLevel logrus.Level) {
@@ -152,15 +84,5 @@ func SetLogLevel(logLevel logrus.Level) {
 }
 
 func SystemMigrate(ociRuntimeRequired string) error {
-	logLevelString := LogLevel.String()
-	args := []string{"--log-level", logLevelString, "system", "migrate"}
-	if ociRuntimeRequired != "" {
-		args = append(args, []string{"--new-runtime", ociRuntimeRequired}...)
-	}
-
-	if err := shell.Run("podman", nil, nil, nil, args...); err != nil {
-		return err
-	}
-
-	return nil
+	return backend.SystemMigrate(ociRuntimeRequired)
 }
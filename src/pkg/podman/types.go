@@ -0,0 +1,178 @@
+/*
+ * Copyright © 2019 – 2020 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Container is a single entry from `podman ps --format json`, decoded into a typed struct
+// instead of a bag of interface{} values.
+type Container struct {
+	ID      string            `json:"Id"`
+	Names   []string          `json:"-"`
+	Image   string            `json:"Image"`
+	ImageID string            `json:"ImageID"`
+	Created int64             `json:"Created"`
+	State   string            `json:"State"`
+	Status  string            `json:"Status"`
+	Labels  map[string]string `json:"Labels"`
+	Mounts  []string          `json:"Mounts"`
+}
+
+// UnmarshalJSON handles the Names field, which is a single string on Podman v1 and a
+// []string from v2 onward.
+func (c *Container) UnmarshalJSON(data []byte) error {
+	type containerAlias Container
+	aux := struct {
+		Names json.RawMessage `json:"Names"`
+		*containerAlias
+	}{containerAlias: (*containerAlias)(c)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if len(aux.Names) == 0 {
+		return nil
+	}
+
+	var asSlice []string
+	if err := json.Unmarshal(aux.Names, &asSlice); err == nil {
+		c.Names = asSlice
+		return nil
+	}
+
+	var asString string
+	if err := json.Unmarshal(aux.Names, &asString); err == nil {
+		if asString != "" {
+			c.Names = []string{asString}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("podman: unsupported shape for Names field: %s", aux.Names)
+}
+
+// Image is a single entry from `podman images --format json`, decoded into a typed struct
+// instead of a bag of interface{} values.
+type Image struct {
+	ID          string            `json:"Id"`
+	RepoTags    []string          `json:"RepoTags"`
+	RepoDigests []string          `json:"RepoDigests"`
+	Created     int64             `json:"Created"`
+	Size        int64             `json:"Size"`
+	Labels      map[string]string `json:"Labels"`
+}
+
+// ContainerInspect is the subset of `podman inspect --type container` toolbox actually
+// looks at.
+type ContainerInspect struct {
+	ID     string `json:"Id"`
+	Name   string `json:"Name"`
+	Config struct {
+		Labels     map[string]string `json:"Labels"`
+		Entrypoint []string          `json:"Entrypoint"`
+	} `json:"Config"`
+	Mounts []struct {
+		Type        string `json:"Type"`
+		Source      string `json:"Source"`
+		Destination string `json:"Destination"`
+	} `json:"Mounts"`
+}
+
+// ImageInspect is the subset of `podman inspect --type image` toolbox actually looks at.
+type ImageInspect struct {
+	ID     string            `json:"Id"`
+	Labels map[string]string `json:"Labels"`
+	Config struct {
+		Labels map[string]string `json:"Labels"`
+	} `json:"Config"`
+}
+
+// GetContainersTyped behaves like GetContainers, but decodes the result into a slice of
+// Container instead of leaving every field as interface{}.
+func GetContainersTyped(args ...string) ([]Container, error) {
+	raw, err := GetContainers(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var containers []Container
+	if err := reencode(raw, &containers); err != nil {
+		return nil, err
+	}
+
+	return containers, nil
+}
+
+// GetImagesTyped behaves like GetImages, but decodes the result into a slice of Image
+// instead of leaving every field as interface{}.
+func GetImagesTyped(args ...string) ([]Image, error) {
+	raw, err := GetImages(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var images []Image
+	if err := reencode(raw, &images); err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+// InspectContainer behaves like Inspect("container", target), but decodes the result into
+// a ContainerInspect instead of leaving every field as interface{}.
+func InspectContainer(target string) (ContainerInspect, error) {
+	var info ContainerInspect
+
+	raw, err := Inspect("container", target)
+	if err != nil {
+		return info, err
+	}
+
+	err = reencode(raw, &info)
+	return info, err
+}
+
+// InspectImage behaves like Inspect("image", target), but decodes the result into an
+// ImageInspect instead of leaving every field as interface{}.
+func InspectImage(target string) (ImageInspect, error) {
+	var info ImageInspect
+
+	raw, err := Inspect("image", target)
+	if err != nil {
+		return info, err
+	}
+
+	err = reencode(raw, &info)
+	return info, err
+}
+
+// reencode round-trips v through JSON, so that the untyped map[string]interface{} results
+// already decoded by the CLI and REST backends can be re-decoded into a typed struct
+// without every caller having to do it by hand.
+func reencode(v interface{}, out interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright © 2019 – 2020 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestContainerUnmarshalJSONNamesSliceV2(t *testing.T) {
+	var c Container
+	raw := `{"Id":"abc","Names":["toolbox-fedora"],"State":"running"}`
+
+	if err := json.Unmarshal([]byte(raw), &c); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if want := []string{"toolbox-fedora"}; !reflect.DeepEqual(c.Names, want) {
+		t.Errorf("got Names=%v, want %v", c.Names, want)
+	}
+}
+
+func TestContainerUnmarshalJSONNamesStringV1(t *testing.T) {
+	var c Container
+	raw := `{"Id":"abc","Names":"toolbox-fedora","State":"running"}`
+
+	if err := json.Unmarshal([]byte(raw), &c); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if want := []string{"toolbox-fedora"}; !reflect.DeepEqual(c.Names, want) {
+		t.Errorf("got Names=%v, want %v", c.Names, want)
+	}
+}
+
+func TestContainerUnmarshalJSONNamesMissing(t *testing.T) {
+	var c Container
+	raw := `{"Id":"abc","State":"running"}`
+
+	if err := json.Unmarshal([]byte(raw), &c); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if c.Names != nil {
+		t.Errorf("got Names=%v, want nil", c.Names)
+	}
+}
+
+func TestContainerUnmarshalJSONNamesUnsupportedShape(t *testing.T) {
+	var c Container
+	raw := `{"Id":"abc","Names":42}`
+
+	if err := json.Unmarshal([]byte(raw), &c); err == nil {
+		t.Error("expected an error for an unsupported Names shape, got nil")
+	}
+}
+
+func TestReencodeDecodesContainerFields(t *testing.T) {
+	raw := []map[string]interface{}{
+		{
+			"Id":     "abc123",
+			"Names":  []interface{}{"toolbox-fedora"},
+			"State":  "running",
+			"Labels": map[string]interface{}{"com.github.containers.toolbox": "true"},
+		},
+	}
+
+	var containers []Container
+	if err := reencode(raw, &containers); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(containers) != 1 {
+		t.Fatalf("got %d containers, want 1", len(containers))
+	}
+
+	c := containers[0]
+	if c.ID != "abc123" {
+		t.Errorf("got ID=%q, want %q", c.ID, "abc123")
+	}
+	if want := []string{"toolbox-fedora"}; !reflect.DeepEqual(c.Names, want) {
+		t.Errorf("got Names=%v, want %v", c.Names, want)
+	}
+	if c.Labels["com.github.containers.toolbox"] != "true" {
+		t.Errorf("got Labels=%v, missing expected label", c.Labels)
+	}
+}
@@ -0,0 +1,180 @@
+/*
+ * Copyright © 2019 – 2020 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// versionPattern matches a semver-ish Podman version string, eg. "1.8.2", "2.5.1-dev" or
+// "4.0.0-rc2". Podman doesn't always ship strict semver (build metadata is never used, and
+// pre-release suffixes are free-form), so this is intentionally looser than a full semver
+// grammar.
+var versionPattern = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)(?:-(.+))?$`)
+
+// Version is a parsed Podman version. Comparing Versions with GTE/LT is the replacement
+// for comparing raw version strings, which broke down on pre-release suffixes like "-dev"
+// and "-rc2".
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+// ParseVersion parses a Podman version string (eg. "1.0.0", "2.5.1-dev") into a Version.
+func ParseVersion(raw string) (Version, error) {
+	matches := versionPattern.FindStringSubmatch(strings.TrimSpace(raw))
+	if matches == nil {
+		return Version{}, fmt.Errorf("invalid Podman version string %q", raw)
+	}
+
+	major, _ := strconv.Atoi(matches[1])
+	minor, _ := strconv.Atoi(matches[2])
+	patch, _ := strconv.Atoi(matches[3])
+
+	return Version{Major: major, Minor: minor, Patch: patch, Prerelease: matches[4]}, nil
+}
+
+// Compare returns -1, 0 or 1 depending on whether v is less than, equal to, or greater
+// than other. A missing Prerelease sorts higher than any non-empty one, matching how
+// Podman releases (no suffix) rank above their own release candidates.
+func (v Version) Compare(other Version) int {
+	if v.Major != other.Major {
+		return compareInt(v.Major, other.Major)
+	}
+	if v.Minor != other.Minor {
+		return compareInt(v.Minor, other.Minor)
+	}
+	if v.Patch != other.Patch {
+		return compareInt(v.Patch, other.Patch)
+	}
+
+	switch {
+	case v.Prerelease == other.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case other.Prerelease == "":
+		return -1
+	default:
+		return strings.Compare(v.Prerelease, other.Prerelease)
+	}
+}
+
+// GTE returns true if v is equal to or higher than other.
+func (v Version) GTE(other Version) bool {
+	return v.Compare(other) >= 0
+}
+
+// LT returns true if v is strictly lower than other.
+func (v Version) LT(other Version) bool {
+	return v.Compare(other) < 0
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Feature gates: the minimum Podman Version each named feature requires. Keeping this
+// matrix in one place avoids the version-string literals it replaces being scattered
+// across every caller that needs to know whether a given Podman supports a given flag.
+const (
+	FeatureUserNSKeepID             = "userns-keep-id"
+	FeatureSystemMigrateNewRuntime  = "system-migrate-new-runtime"
+	FeatureShortNetworkInterfaceKey = "short-network-interface-key"
+)
+
+var featureGates = map[string]Version{
+	FeatureUserNSKeepID:             {Major: 1, Minor: 8, Patch: 2},
+	FeatureSystemMigrateNewRuntime:  {Major: 2, Minor: 0, Patch: 0},
+	FeatureShortNetworkInterfaceKey: {Major: 2, Minor: 1, Patch: 0},
+}
+
+var (
+	cachedVersion   Version
+	cachedVersionOK bool
+	cachedVersionMu sync.Mutex
+)
+
+// currentVersion returns the parsed Version of the Podman the package is talking to. A
+// successfully parsed version is cached so repeated FeatureSupported calls don't each
+// re-run GetVersion; a failure (Podman/its socket unreachable, unparseable version, etc.)
+// is never cached, so the next call retries instead of being stuck returning that same
+// error for the rest of the process's lifetime.
+func currentVersion() (Version, error) {
+	cachedVersionMu.Lock()
+	if cachedVersionOK {
+		version := cachedVersion
+		cachedVersionMu.Unlock()
+		return version, nil
+	}
+	cachedVersionMu.Unlock()
+
+	raw, err := GetVersion()
+	if err != nil {
+		return Version{}, err
+	}
+
+	version, err := ParseVersion(raw)
+	if err != nil {
+		return Version{}, err
+	}
+
+	cachedVersionMu.Lock()
+	cachedVersion = version
+	cachedVersionOK = true
+	cachedVersionMu.Unlock()
+
+	return version, nil
+}
+
+// FeatureSupported returns whether the Podman version toolbox is talking to supports the
+// named feature gate. It returns false for an unknown feature name or if the Podman
+// version couldn't be determined.
+func FeatureSupported(name string) bool {
+	minVersion, ok := featureGates[name]
+	if !ok {
+		return false
+	}
+
+	version, err := currentVersion()
+	if err != nil {
+		return false
+	}
+
+	return version.GTE(minVersion)
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright © 2019 – 2020 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Version
+	}{
+		{"1.0.0", Version{Major: 1, Minor: 0, Patch: 0}},
+		{"2.5.1-dev", Version{Major: 2, Minor: 5, Patch: 1, Prerelease: "dev"}},
+		{"v4.0.0-rc2", Version{Major: 4, Minor: 0, Patch: 0, Prerelease: "rc2"}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.raw)
+		if err != nil {
+			t.Errorf("ParseVersion(%q) returned error: %s", tt.raw, err)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("ParseVersion(%q) = %+v, want %+v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestParseVersionInvalid(t *testing.T) {
+	if _, err := ParseVersion("not-a-version"); err == nil {
+		t.Error("expected an error for an invalid version string, got nil")
+	}
+}
+
+func TestVersionGTE(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"1.8.2", "1.8.2", true},
+		{"1.9.0", "1.8.2", true},
+		{"1.8.1", "1.8.2", false},
+		{"2.0.0-rc2", "2.0.0", false},
+		{"2.0.0", "2.0.0-rc2", true},
+	}
+
+	for _, tt := range tests {
+		a, err := ParseVersion(tt.a)
+		if err != nil {
+			t.Fatalf("ParseVersion(%q): %s", tt.a, err)
+		}
+
+		b, err := ParseVersion(tt.b)
+		if err != nil {
+			t.Fatalf("ParseVersion(%q): %s", tt.b, err)
+		}
+
+		if got := a.GTE(b); got != tt.want {
+			t.Errorf("Version(%s).GTE(%s) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestVersionLT(t *testing.T) {
+	a, _ := ParseVersion("1.8.1")
+	b, _ := ParseVersion("1.8.2")
+
+	if !a.LT(b) {
+		t.Errorf("expected %s to be lower than %s", a, b)
+	}
+
+	if b.LT(a) {
+		t.Errorf("did not expect %s to be lower than %s", b, a)
+	}
+}
+
+func TestVersionString(t *testing.T) {
+	v := Version{Major: 2, Minor: 5, Patch: 1, Prerelease: "dev"}
+	if got, want := v.String(), "2.5.1-dev"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestCurrentVersionDoesNotCacheFailure(t *testing.T) {
+	origBackend := backend
+	defer func() { backend = origBackend }()
+
+	cachedVersionMu.Lock()
+	cachedVersion = Version{}
+	cachedVersionOK = false
+	cachedVersionMu.Unlock()
+
+	backend = &fakeBackend{versionErr: errFakeVersionUnreachable}
+
+	if _, err := currentVersion(); err == nil {
+		t.Fatal("expected currentVersion to surface the backend error, got nil")
+	}
+
+	backend = &fakeBackend{version: "1.8.2"}
+
+	got, err := currentVersion()
+	if err != nil {
+		t.Fatalf("expected currentVersion to recover once the backend is reachable, got: %s", err)
+	}
+
+	want, _ := ParseVersion("1.8.2")
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright © 2019 – 2020 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package shell
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// killGracePeriod is how long RunContext waits after sending SIGTERM to a child process
+// before escalating to SIGKILL. It's a var rather than a const so tests can shrink it.
+var killGracePeriod = 5 * time.Second
+
+// Run executes name with args, connecting stdin, stdout and stderr as given. It is a thin
+// wrapper around RunContext using context.Background, ie. it cannot be cancelled.
+func Run(name string, stdin io.Reader, stdout, stderr io.Writer, args ...string) error {
+	return RunContext(context.Background(), name, stdin, stdout, stderr, args...)
+}
+
+// RunContext behaves like Run, except the child process is terminated as soon as ctx is
+// cancelled or its deadline is exceeded: RunContext sends SIGTERM first, then escalates to
+// SIGKILL if the process hasn't exited within killGracePeriod.
+func RunContext(ctx context.Context, name string, stdin io.Reader, stdout, stderr io.Writer, args ...string) error {
+	logrus.Debugf("Running %s", formatCommand(name, args))
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-waitErr:
+		return err
+	case <-ctx.Done():
+		terminate(cmd, waitErr)
+		return ctx.Err()
+	}
+}
+
+// terminate sends SIGTERM to cmd's process and blocks until it exits, escalating to
+// SIGKILL if it hasn't within killGracePeriod.
+func terminate(cmd *exec.Cmd, waitErr <-chan error) {
+	if cmd.Process == nil {
+		return
+	}
+
+	_ = cmd.Process.Signal(syscall.SIGTERM)
+
+	timer := time.NewTimer(killGracePeriod)
+	defer timer.Stop()
+
+	select {
+	case <-waitErr:
+	case <-timer.C:
+		_ = cmd.Process.Kill()
+		<-waitErr
+	}
+}
+
+func formatCommand(name string, args []string) string {
+	return strings.TrimSpace(name + " " + strings.Join(args, " "))
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright © 2019 – 2020 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package shell
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunContextReturnsWithoutCancellation(t *testing.T) {
+	if err := RunContext(context.Background(), "true", nil, nil, nil); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestRunContextSIGTERM(t *testing.T) {
+	origGracePeriod := killGracePeriod
+	killGracePeriod = 2 * time.Second
+	defer func() { killGracePeriod = origGracePeriod }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- RunContext(ctx, "bash", nil, nil, nil, "-c", "trap 'exit 0' TERM; sleep 30 & wait")
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got error %v, want context.Canceled", err)
+		}
+	case <-time.After(killGracePeriod + 2*time.Second):
+		t.Fatal("RunContext did not return after ctx was cancelled")
+	}
+}
+
+func TestRunContextEscalatesToSIGKILL(t *testing.T) {
+	origGracePeriod := killGracePeriod
+	killGracePeriod = 200 * time.Millisecond
+	defer func() { killGracePeriod = origGracePeriod }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- RunContext(ctx, "sh", nil, nil, nil, "-c", "trap '' TERM; sleep 30")
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got error %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunContext did not escalate to SIGKILL in time")
+	}
+}